@@ -0,0 +1,316 @@
+// Copyright 2026 The gitprotocolio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file is hand-written, not protoc output: it implements Marshal and
+// Unmarshal directly instead of relying on struct-tag reflection, which
+// github.com/golang/protobuf/proto cannot use to discover oneof wrapper
+// types on its own. Keep it in sync with gitprotocolv2.proto by hand.
+
+package grpctransport
+
+import "fmt"
+
+// RequestChunk is the wire representation of a ProtocolV2RequestChunk. Exactly
+// one field is set per message, mirroring the discriminated-union shape of
+// the Go type.
+type RequestChunk struct {
+	// Chunk holds exactly one of *RequestChunk_Command, *RequestChunk_Capability,
+	// *RequestChunk_EndCapability, *RequestChunk_Argument,
+	// *RequestChunk_EndArgument, or *RequestChunk_EndRequest.
+	Chunk isRequestChunk_Chunk
+}
+
+func (m *RequestChunk) Reset()         { *m = RequestChunk{} }
+func (m *RequestChunk) String() string { return fmt.Sprintf("%+v", m.GetChunk()) }
+func (*RequestChunk) ProtoMessage()    {}
+
+type isRequestChunk_Chunk interface {
+	isRequestChunk_Chunk()
+}
+
+type RequestChunk_Command struct {
+	Command string
+}
+
+type RequestChunk_Capability struct {
+	Capability string
+}
+
+type RequestChunk_EndCapability struct {
+	EndCapability bool
+}
+
+type RequestChunk_Argument struct {
+	Argument []byte
+}
+
+type RequestChunk_EndArgument struct {
+	EndArgument bool
+}
+
+type RequestChunk_EndRequest struct {
+	EndRequest bool
+}
+
+func (*RequestChunk_Command) isRequestChunk_Chunk()       {}
+func (*RequestChunk_Capability) isRequestChunk_Chunk()    {}
+func (*RequestChunk_EndCapability) isRequestChunk_Chunk() {}
+func (*RequestChunk_Argument) isRequestChunk_Chunk()      {}
+func (*RequestChunk_EndArgument) isRequestChunk_Chunk()   {}
+func (*RequestChunk_EndRequest) isRequestChunk_Chunk()    {}
+
+func (m *RequestChunk) GetChunk() isRequestChunk_Chunk {
+	if m != nil {
+		return m.Chunk
+	}
+	return nil
+}
+
+func (m *RequestChunk) GetCommand() string {
+	if x, ok := m.GetChunk().(*RequestChunk_Command); ok {
+		return x.Command
+	}
+	return ""
+}
+
+func (m *RequestChunk) GetCapability() string {
+	if x, ok := m.GetChunk().(*RequestChunk_Capability); ok {
+		return x.Capability
+	}
+	return ""
+}
+
+func (m *RequestChunk) GetEndCapability() bool {
+	if x, ok := m.GetChunk().(*RequestChunk_EndCapability); ok {
+		return x.EndCapability
+	}
+	return false
+}
+
+func (m *RequestChunk) GetArgument() []byte {
+	if x, ok := m.GetChunk().(*RequestChunk_Argument); ok {
+		return x.Argument
+	}
+	return nil
+}
+
+func (m *RequestChunk) GetEndArgument() bool {
+	if x, ok := m.GetChunk().(*RequestChunk_EndArgument); ok {
+		return x.EndArgument
+	}
+	return false
+}
+
+func (m *RequestChunk) GetEndRequest() bool {
+	if x, ok := m.GetChunk().(*RequestChunk_EndRequest); ok {
+		return x.EndRequest
+	}
+	return false
+}
+
+// Marshal encodes m using the proto3 wire format.
+func (m *RequestChunk) Marshal() ([]byte, error) {
+	var buf []byte
+	switch v := m.GetChunk().(type) {
+	case *RequestChunk_Command:
+		buf = appendBytesField(buf, 1, []byte(v.Command))
+	case *RequestChunk_Capability:
+		buf = appendBytesField(buf, 2, []byte(v.Capability))
+	case *RequestChunk_EndCapability:
+		buf = appendVarintField(buf, 3, v.EndCapability)
+	case *RequestChunk_Argument:
+		buf = appendBytesField(buf, 4, v.Argument)
+	case *RequestChunk_EndArgument:
+		buf = appendVarintField(buf, 5, v.EndArgument)
+	case *RequestChunk_EndRequest:
+		buf = appendVarintField(buf, 6, v.EndRequest)
+	}
+	return buf, nil
+}
+
+// Size returns the length of the encoding returned by Marshal.
+func (m *RequestChunk) Size() int {
+	b, _ := m.Marshal()
+	return len(b)
+}
+
+// Unmarshal decodes the proto3 wire format produced by Marshal into m.
+func (m *RequestChunk) Unmarshal(data []byte) error {
+	if len(data) == 0 {
+		m.Chunk = nil
+		return nil
+	}
+	field, wire, rest, err := consumeTag(data)
+	if err != nil {
+		return err
+	}
+	switch wire {
+	case wireVarint:
+		v, _, err := consumeVarint(rest)
+		if err != nil {
+			return err
+		}
+		switch field {
+		case 3:
+			m.Chunk = &RequestChunk_EndCapability{EndCapability: v != 0}
+		case 5:
+			m.Chunk = &RequestChunk_EndArgument{EndArgument: v != 0}
+		case 6:
+			m.Chunk = &RequestChunk_EndRequest{EndRequest: v != 0}
+		default:
+			return fmt.Errorf("grpctransport: unexpected varint field %d in RequestChunk", field)
+		}
+	case wireBytes:
+		v, _, err := consumeBytes(rest)
+		if err != nil {
+			return err
+		}
+		switch field {
+		case 1:
+			m.Chunk = &RequestChunk_Command{Command: string(v)}
+		case 2:
+			m.Chunk = &RequestChunk_Capability{Capability: string(v)}
+		case 4:
+			m.Chunk = &RequestChunk_Argument{Argument: v}
+		default:
+			return fmt.Errorf("grpctransport: unexpected length-delimited field %d in RequestChunk", field)
+		}
+	default:
+		return fmt.Errorf("grpctransport: unexpected wire type %d in RequestChunk", wire)
+	}
+	return nil
+}
+
+// ResponseChunk is the wire representation of a ProtocolV2ResponseChunk.
+type ResponseChunk struct {
+	// Chunk holds exactly one of *ResponseChunk_Data, *ResponseChunk_EndResponse,
+	// or *ResponseChunk_Delim.
+	Chunk isResponseChunk_Chunk
+}
+
+func (m *ResponseChunk) Reset()         { *m = ResponseChunk{} }
+func (m *ResponseChunk) String() string { return fmt.Sprintf("%+v", m.GetChunk()) }
+func (*ResponseChunk) ProtoMessage()    {}
+
+type isResponseChunk_Chunk interface {
+	isResponseChunk_Chunk()
+}
+
+type ResponseChunk_Data struct {
+	Data []byte
+}
+
+type ResponseChunk_EndResponse struct {
+	EndResponse bool
+}
+
+// ResponseChunk_Delim marks a section boundary (0001) within a multi-section
+// response, e.g. between acknowledgments, shallow-info, wanted-refs, and the
+// packfile in a fetch response.
+type ResponseChunk_Delim struct {
+	Delim bool
+}
+
+func (*ResponseChunk_Data) isResponseChunk_Chunk()        {}
+func (*ResponseChunk_EndResponse) isResponseChunk_Chunk() {}
+func (*ResponseChunk_Delim) isResponseChunk_Chunk()       {}
+
+func (m *ResponseChunk) GetChunk() isResponseChunk_Chunk {
+	if m != nil {
+		return m.Chunk
+	}
+	return nil
+}
+
+func (m *ResponseChunk) GetData() []byte {
+	if x, ok := m.GetChunk().(*ResponseChunk_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+func (m *ResponseChunk) GetEndResponse() bool {
+	if x, ok := m.GetChunk().(*ResponseChunk_EndResponse); ok {
+		return x.EndResponse
+	}
+	return false
+}
+
+func (m *ResponseChunk) GetDelim() bool {
+	if x, ok := m.GetChunk().(*ResponseChunk_Delim); ok {
+		return x.Delim
+	}
+	return false
+}
+
+// Marshal encodes m using the proto3 wire format.
+func (m *ResponseChunk) Marshal() ([]byte, error) {
+	var buf []byte
+	switch v := m.GetChunk().(type) {
+	case *ResponseChunk_Data:
+		buf = appendBytesField(buf, 1, v.Data)
+	case *ResponseChunk_EndResponse:
+		buf = appendVarintField(buf, 2, v.EndResponse)
+	case *ResponseChunk_Delim:
+		buf = appendVarintField(buf, 3, v.Delim)
+	}
+	return buf, nil
+}
+
+// Size returns the length of the encoding returned by Marshal.
+func (m *ResponseChunk) Size() int {
+	b, _ := m.Marshal()
+	return len(b)
+}
+
+// Unmarshal decodes the proto3 wire format produced by Marshal into m.
+func (m *ResponseChunk) Unmarshal(data []byte) error {
+	if len(data) == 0 {
+		m.Chunk = nil
+		return nil
+	}
+	field, wire, rest, err := consumeTag(data)
+	if err != nil {
+		return err
+	}
+	switch wire {
+	case wireVarint:
+		v, _, err := consumeVarint(rest)
+		if err != nil {
+			return err
+		}
+		switch field {
+		case 2:
+			m.Chunk = &ResponseChunk_EndResponse{EndResponse: v != 0}
+		case 3:
+			m.Chunk = &ResponseChunk_Delim{Delim: v != 0}
+		default:
+			return fmt.Errorf("grpctransport: unexpected varint field %d in ResponseChunk", field)
+		}
+	case wireBytes:
+		v, _, err := consumeBytes(rest)
+		if err != nil {
+			return err
+		}
+		switch field {
+		case 1:
+			m.Chunk = &ResponseChunk_Data{Data: v}
+		default:
+			return fmt.Errorf("grpctransport: unexpected length-delimited field %d in ResponseChunk", field)
+		}
+	default:
+		return fmt.Errorf("grpctransport: unexpected wire type %d in ResponseChunk", wire)
+	}
+	return nil
+}
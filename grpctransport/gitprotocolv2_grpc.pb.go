@@ -0,0 +1,136 @@
+// Copyright 2026 The gitprotocolio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file is hand-written, not protoc-gen-go-grpc output: it implements
+// the GitProtocolV2 client/server stream wrappers directly against
+// grpc.ClientConnInterface/grpc.ServerStream instead of relying on a protoc
+// toolchain that isn't wired into this repo.
+
+package grpctransport
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// GitProtocolV2Client is the client API for GitProtocolV2 service.
+type GitProtocolV2Client interface {
+	// Exchange tunnels a Git smart protocol v2 session: the client streams
+	// RequestChunk messages and reads back ResponseChunk messages until the
+	// server half-closes the stream.
+	Exchange(ctx context.Context, opts ...grpc.CallOption) (GitProtocolV2_ExchangeClient, error)
+}
+
+type gitProtocolV2Client struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewGitProtocolV2Client returns a new GitProtocolV2Client.
+func NewGitProtocolV2Client(cc grpc.ClientConnInterface) GitProtocolV2Client {
+	return &gitProtocolV2Client{cc}
+}
+
+func (c *gitProtocolV2Client) Exchange(ctx context.Context, opts ...grpc.CallOption) (GitProtocolV2_ExchangeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GitProtocolV2_serviceDesc.Streams[0], "/gitprotocolio.grpctransport.GitProtocolV2/Exchange", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &gitProtocolV2ExchangeClient{stream}, nil
+}
+
+// GitProtocolV2_ExchangeClient is the client-side stream for Exchange.
+type GitProtocolV2_ExchangeClient interface {
+	Send(*RequestChunk) error
+	Recv() (*ResponseChunk, error)
+	grpc.ClientStream
+}
+
+type gitProtocolV2ExchangeClient struct {
+	grpc.ClientStream
+}
+
+func (x *gitProtocolV2ExchangeClient) Send(m *RequestChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *gitProtocolV2ExchangeClient) Recv() (*ResponseChunk, error) {
+	m := new(ResponseChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GitProtocolV2Server is the server API for GitProtocolV2 service.
+type GitProtocolV2Server interface {
+	Exchange(GitProtocolV2_ExchangeServer) error
+}
+
+// UnimplementedGitProtocolV2Server can be embedded to have forward compatible
+// implementations.
+type UnimplementedGitProtocolV2Server struct{}
+
+func (*UnimplementedGitProtocolV2Server) Exchange(GitProtocolV2_ExchangeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Exchange not implemented")
+}
+
+// RegisterGitProtocolV2Server registers srv with s.
+func RegisterGitProtocolV2Server(s *grpc.Server, srv GitProtocolV2Server) {
+	s.RegisterService(&_GitProtocolV2_serviceDesc, srv)
+}
+
+func _GitProtocolV2_Exchange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GitProtocolV2Server).Exchange(&gitProtocolV2ExchangeServer{stream})
+}
+
+// GitProtocolV2_ExchangeServer is the server-side stream for Exchange.
+type GitProtocolV2_ExchangeServer interface {
+	Send(*ResponseChunk) error
+	Recv() (*RequestChunk, error)
+	grpc.ServerStream
+}
+
+type gitProtocolV2ExchangeServer struct {
+	grpc.ServerStream
+}
+
+func (x *gitProtocolV2ExchangeServer) Send(m *ResponseChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *gitProtocolV2ExchangeServer) Recv() (*RequestChunk, error) {
+	m := new(RequestChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _GitProtocolV2_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "gitprotocolio.grpctransport.GitProtocolV2",
+	HandlerType: (*GitProtocolV2Server)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Exchange",
+			Handler:       _GitProtocolV2_Exchange_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "gitprotocolv2.proto",
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 The gitprotocolio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpctransport tunnels Git smart protocol v2 sessions over a gRPC
+// bidirectional streaming service, so that the pkt-line message boundaries
+// modeled by gitprotocolio are preserved across infrastructure (meshes, auth
+// interceptors, load balancers) that only understands gRPC framing.
+package grpctransport
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/sluongng/gitprotocolio"
+	"google.golang.org/grpc"
+)
+
+// ChunkToProto converts a ProtocolV2RequestChunk into its gRPC wire
+// representation.
+func ChunkToProto(c *gitprotocolio.ProtocolV2RequestChunk) *RequestChunk {
+	switch {
+	case c.Command != "":
+		return &RequestChunk{Chunk: &RequestChunk_Command{Command: c.Command}}
+	case c.Capability != "":
+		return &RequestChunk{Chunk: &RequestChunk_Capability{Capability: c.Capability}}
+	case c.EndCapability:
+		return &RequestChunk{Chunk: &RequestChunk_EndCapability{EndCapability: true}}
+	case len(c.Argument) != 0:
+		return &RequestChunk{Chunk: &RequestChunk_Argument{Argument: c.Argument}}
+	case c.EndArgument:
+		return &RequestChunk{Chunk: &RequestChunk_EndArgument{EndArgument: true}}
+	case c.EndRequest:
+		return &RequestChunk{Chunk: &RequestChunk_EndRequest{EndRequest: true}}
+	}
+	panic("impossible chunk")
+}
+
+// ProtoToChunk converts a wire RequestChunk back into a
+// ProtocolV2RequestChunk.
+func ProtoToChunk(c *RequestChunk) *gitprotocolio.ProtocolV2RequestChunk {
+	switch v := c.GetChunk().(type) {
+	case *RequestChunk_Command:
+		return &gitprotocolio.ProtocolV2RequestChunk{Command: v.Command}
+	case *RequestChunk_Capability:
+		return &gitprotocolio.ProtocolV2RequestChunk{Capability: v.Capability}
+	case *RequestChunk_EndCapability:
+		return &gitprotocolio.ProtocolV2RequestChunk{EndCapability: v.EndCapability}
+	case *RequestChunk_Argument:
+		return &gitprotocolio.ProtocolV2RequestChunk{Argument: v.Argument}
+	case *RequestChunk_EndArgument:
+		return &gitprotocolio.ProtocolV2RequestChunk{EndArgument: v.EndArgument}
+	case *RequestChunk_EndRequest:
+		return &gitprotocolio.ProtocolV2RequestChunk{EndRequest: v.EndRequest}
+	}
+	panic(fmt.Sprintf("unexpected chunk: %#v", c))
+}
+
+// packetToResponseProto converts a raw pkt-line packet, as returned by
+// PacketScanner.Packet, into its gRPC wire representation.
+func packetToResponseProto(pkt interface{}) (*ResponseChunk, error) {
+	switch p := pkt.(type) {
+	case gitprotocolio.BytesPacket:
+		return &ResponseChunk{Chunk: &ResponseChunk_Data{Data: []byte(p)}}, nil
+	case gitprotocolio.DelimPacket:
+		return &ResponseChunk{Chunk: &ResponseChunk_Delim{Delim: true}}, nil
+	case gitprotocolio.FlushPacket:
+		return &ResponseChunk{Chunk: &ResponseChunk_EndResponse{EndResponse: true}}, nil
+	default:
+		return nil, fmt.Errorf("grpctransport: unexpected packet: %#v", pkt)
+	}
+}
+
+// writeResponseChunk re-serializes a wire ResponseChunk back into the
+// pkt-line encoding expected by a real Git client.
+func writeResponseChunk(w io.Writer, c *ResponseChunk) error {
+	switch v := c.GetChunk().(type) {
+	case *ResponseChunk_Data:
+		_, err := w.Write(gitprotocolio.BytesPacket(v.Data).EncodeToPktLine())
+		return err
+	case *ResponseChunk_Delim:
+		_, err := w.Write(gitprotocolio.DelimPacket{}.EncodeToPktLine())
+		return err
+	case *ResponseChunk_EndResponse:
+		_, err := w.Write(gitprotocolio.FlushPacket{}.EncodeToPktLine())
+		return err
+	default:
+		return fmt.Errorf("grpctransport: unexpected response chunk: %#v", c)
+	}
+}
+
+// NewClientStream drives a real Git client's stdio through a GitProtocolV2
+// gRPC stream on cc: pkt-lines read from r are parsed with
+// gitprotocolio.ProtocolV2Request and sent as RequestChunk messages, and
+// ResponseChunk messages received back are re-serialized as pkt-lines to w.
+// It blocks until the client has sent its whole request and the server has
+// sent back its whole response.
+func NewClientStream(cc *grpc.ClientConn, r io.Reader, w io.Writer) error {
+	stream, err := NewGitProtocolV2Client(cc).Exchange(context.Background())
+	if err != nil {
+		return err
+	}
+
+	req := gitprotocolio.NewProtocolV2Request(r)
+	sendErrc := make(chan error, 1)
+	go func() {
+		for req.Scan() {
+			if err := stream.Send(ChunkToProto(req.Chunk())); err != nil {
+				sendErrc <- err
+				return
+			}
+		}
+		sendErrc <- stream.CloseSend()
+	}()
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := writeResponseChunk(w, resp); err != nil {
+			return err
+		}
+	}
+	if err := <-sendErrc; err != nil {
+		return err
+	}
+	return req.Err()
+}
+
+// ServerHandler implements GitProtocolV2Server by forwarding a stream's
+// RequestChunk messages into a backend, e.g. the stdin of a spawned
+// `git upload-pack --stateless-rpc`, and forwarding the backend's pkt-line
+// output back out as ResponseChunk messages.
+type ServerHandler struct {
+	// NewBackend is called once per Exchange call and returns the
+	// io.ReadWriteCloser the stream should be proxied to. It is closed when
+	// Exchange returns, so that a backend backed by a spawned process (e.g.
+	// its stdio pipes) doesn't leak past the RPC that started it.
+	NewBackend func() (io.ReadWriteCloser, error)
+}
+
+// Exchange implements GitProtocolV2Server.
+func (h *ServerHandler) Exchange(stream GitProtocolV2_ExchangeServer) error {
+	backend, err := h.NewBackend()
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	recvErrc := make(chan error, 1)
+	go func() {
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				recvErrc <- nil
+				return
+			}
+			if err != nil {
+				recvErrc <- err
+				return
+			}
+			if _, err := backend.Write(ProtoToChunk(chunk).EncodeToPktLine()); err != nil {
+				recvErrc <- err
+				return
+			}
+		}
+	}()
+
+	scanner := gitprotocolio.NewPacketScanner(backend)
+	for scanner.Scan() {
+		chunk, err := packetToResponseProto(scanner.Packet())
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return <-recvErrc
+}
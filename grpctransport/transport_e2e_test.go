@@ -0,0 +1,106 @@
+// Copyright 2026 The gitprotocolio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpctransport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sluongng/gitprotocolio"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeBackend stands in for a spawned `git upload-pack --stateless-rpc`: it
+// records everything written to it and serves a canned response, regardless
+// of how much of that response has actually been requested yet.
+type fakeBackend struct {
+	written bytes.Buffer
+	resp    *bytes.Reader
+	closed  bool
+}
+
+func (b *fakeBackend) Write(p []byte) (int, error) { return b.written.Write(p) }
+func (b *fakeBackend) Read(p []byte) (int, error)  { return b.resp.Read(p) }
+func (b *fakeBackend) Close() error {
+	b.closed = true
+	return nil
+}
+
+func bufconnDialer(lis *bufconn.Listener) func(context.Context, string) (net.Conn, error) {
+	return func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}
+}
+
+// TestNewClientStreamEndToEnd drives NewClientStream and ServerHandler.Exchange
+// against each other over a real in-process gRPC connection, exercising the
+// full request and response pkt-line round trip this package exists for.
+func TestNewClientStreamEndToEnd(t *testing.T) {
+	request := bytes.Join([][]byte{
+		gitprotocolio.BytesPacket("command=fetch\n").EncodeToPktLine(),
+		gitprotocolio.DelimPacket{}.EncodeToPktLine(),
+		gitprotocolio.BytesPacket("want deadbeef\n").EncodeToPktLine(),
+		gitprotocolio.FlushPacket{}.EncodeToPktLine(),
+		gitprotocolio.FlushPacket{}.EncodeToPktLine(),
+	}, nil)
+	response := bytes.Join([][]byte{
+		gitprotocolio.BytesPacket("acknowledgments\n").EncodeToPktLine(),
+		gitprotocolio.DelimPacket{}.EncodeToPktLine(),
+		gitprotocolio.BytesPacket("packfile data").EncodeToPktLine(),
+		gitprotocolio.FlushPacket{}.EncodeToPktLine(),
+	}, nil)
+
+	lis := bufconn.Listen(1 << 20)
+	defer lis.Close()
+
+	backend := &fakeBackend{resp: bytes.NewReader(response)}
+	srv := grpc.NewServer()
+	RegisterGitProtocolV2Server(srv, &ServerHandler{
+		NewBackend: func() (io.ReadWriteCloser, error) { return backend, nil },
+	})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	cc, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(bufconnDialer(lis)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("DialContext() = %v", err)
+	}
+	defer cc.Close()
+
+	var out bytes.Buffer
+	if err := NewClientStream(cc, bytes.NewReader(request), &out); err != nil {
+		t.Fatalf("NewClientStream() = %v", err)
+	}
+
+	if !bytes.Equal(backend.written.Bytes(), request) {
+		t.Errorf("backend received %q, want %q", backend.written.Bytes(), request)
+	}
+	if !bytes.Equal(out.Bytes(), response) {
+		t.Errorf("client received %q, want %q", out.Bytes(), response)
+	}
+	if !backend.closed {
+		t.Error("backend was not closed after Exchange completed")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 The gitprotocolio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpctransport
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/sluongng/gitprotocolio"
+)
+
+func TestChunkToProtoRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		chunk *gitprotocolio.ProtocolV2RequestChunk
+	}{
+		{"command", &gitprotocolio.ProtocolV2RequestChunk{Command: "fetch"}},
+		{"capability", &gitprotocolio.ProtocolV2RequestChunk{Capability: "agent=git/2.40.0"}},
+		{"end capability", &gitprotocolio.ProtocolV2RequestChunk{EndCapability: true}},
+		{"argument", &gitprotocolio.ProtocolV2RequestChunk{Argument: []byte("want deadbeef\n")}},
+		{"end argument", &gitprotocolio.ProtocolV2RequestChunk{EndArgument: true}},
+		{"end request", &gitprotocolio.ProtocolV2RequestChunk{EndRequest: true}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ProtoToChunk(ChunkToProto(tc.chunk))
+			if !reflect.DeepEqual(got, tc.chunk) {
+				t.Errorf("round trip = %#v, want %#v", got, tc.chunk)
+			}
+		})
+	}
+}
+
+func TestRequestChunkMarshalUnmarshal(t *testing.T) {
+	tests := []*RequestChunk{
+		{Chunk: &RequestChunk_Command{Command: "ls-refs"}},
+		{Chunk: &RequestChunk_Capability{Capability: "object-format=sha1"}},
+		{Chunk: &RequestChunk_EndCapability{EndCapability: true}},
+		{Chunk: &RequestChunk_Argument{Argument: []byte("ref-prefix refs/heads/\n")}},
+		{Chunk: &RequestChunk_EndArgument{EndArgument: true}},
+		{Chunk: &RequestChunk_EndRequest{EndRequest: true}},
+	}
+	for _, want := range tests {
+		data, err := want.Marshal()
+		if err != nil {
+			t.Fatalf("Marshal(%#v): %v", want, err)
+		}
+		got := &RequestChunk{}
+		if err := got.Unmarshal(data); err != nil {
+			t.Fatalf("Unmarshal(%x): %v", data, err)
+		}
+		if !reflect.DeepEqual(got.GetChunk(), want.GetChunk()) {
+			t.Errorf("round trip = %#v, want %#v", got.GetChunk(), want.GetChunk())
+		}
+	}
+}
+
+func TestResponseChunkMarshalUnmarshal(t *testing.T) {
+	tests := []*ResponseChunk{
+		{Chunk: &ResponseChunk_Data{Data: []byte("0123456789abcdef")}},
+		{Chunk: &ResponseChunk_Delim{Delim: true}},
+		{Chunk: &ResponseChunk_EndResponse{EndResponse: true}},
+	}
+	for _, want := range tests {
+		data, err := want.Marshal()
+		if err != nil {
+			t.Fatalf("Marshal(%#v): %v", want, err)
+		}
+		got := &ResponseChunk{}
+		if err := got.Unmarshal(data); err != nil {
+			t.Fatalf("Unmarshal(%x): %v", data, err)
+		}
+		switch w := want.GetChunk().(type) {
+		case *ResponseChunk_Data:
+			g, ok := got.GetChunk().(*ResponseChunk_Data)
+			if !ok || !bytes.Equal(g.Data, w.Data) {
+				t.Errorf("round trip = %#v, want %#v", got.GetChunk(), want.GetChunk())
+			}
+		default:
+			if !reflect.DeepEqual(got.GetChunk(), want.GetChunk()) {
+				t.Errorf("round trip = %#v, want %#v", got.GetChunk(), want.GetChunk())
+			}
+		}
+	}
+}
+
+func TestPacketToResponseProtoRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		pkt  interface{}
+	}{
+		{"bytes", gitprotocolio.BytesPacket("shallow-info\n")},
+		{"delim", gitprotocolio.DelimPacket{}},
+		{"flush", gitprotocolio.FlushPacket{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			chunk, err := packetToResponseProto(tc.pkt)
+			if err != nil {
+				t.Fatalf("packetToResponseProto(%#v): %v", tc.pkt, err)
+			}
+			var buf bytes.Buffer
+			if err := writeResponseChunk(&buf, chunk); err != nil {
+				t.Fatalf("writeResponseChunk(%#v): %v", chunk, err)
+			}
+			var want []byte
+			switch p := tc.pkt.(type) {
+			case gitprotocolio.BytesPacket:
+				want = p.EncodeToPktLine()
+			case gitprotocolio.DelimPacket:
+				want = p.EncodeToPktLine()
+			case gitprotocolio.FlushPacket:
+				want = p.EncodeToPktLine()
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("re-encoded = %q, want %q", buf.Bytes(), want)
+			}
+		})
+	}
+}
+
+func TestPacketToResponseProtoRejectsUnknownPacket(t *testing.T) {
+	if _, err := packetToResponseProto(gitprotocolio.DelimPacket{}.EncodeToPktLine()); err == nil {
+		t.Error("packetToResponseProto(raw bytes) = nil error, want an error for an unrecognized packet type")
+	}
+}
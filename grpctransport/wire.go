@@ -0,0 +1,76 @@
+// Copyright 2026 The gitprotocolio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpctransport
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// wireVarint and wireBytes are the two protobuf wire types RequestChunk and
+// ResponseChunk use: every field is either a bool (encoded as a varint) or a
+// string/bytes (encoded length-delimited).
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func appendTag(buf []byte, field, wire int) []byte {
+	return binary.AppendUvarint(buf, uint64(field)<<3|uint64(wire))
+}
+
+func appendVarintField(buf []byte, field int, v bool) []byte {
+	buf = appendTag(buf, field, wireVarint)
+	n := uint64(0)
+	if v {
+		n = 1
+	}
+	return binary.AppendUvarint(buf, n)
+}
+
+func appendBytesField(buf []byte, field int, v []byte) []byte {
+	buf = appendTag(buf, field, wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(v)))
+	return append(buf, v...)
+}
+
+// consumeTag reads a field number and wire type off the front of data.
+func consumeTag(data []byte) (field, wire int, rest []byte, err error) {
+	tag, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, 0, nil, fmt.Errorf("grpctransport: malformed tag")
+	}
+	return int(tag >> 3), int(tag & 0x7), data[n:], nil
+}
+
+func consumeVarint(data []byte) (v uint64, rest []byte, err error) {
+	v, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, nil, fmt.Errorf("grpctransport: malformed varint")
+	}
+	return v, data[n:], nil
+}
+
+func consumeBytes(data []byte) (v, rest []byte, err error) {
+	l, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, nil, fmt.Errorf("grpctransport: malformed length-delimited field")
+	}
+	data = data[n:]
+	if uint64(len(data)) < l {
+		return nil, nil, fmt.Errorf("grpctransport: truncated length-delimited field")
+	}
+	return data[:l], data[l:], nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 The gitprotocolio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package gitprotocolio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ProtocolHandlers holds the callbacks ProtocolMux dispatches to once it has
+// determined which Git wire protocol version a session is using. Each
+// callback receives an already-primed scanner (or request), i.e. one whose
+// first packet is the same packet ProtocolMux peeked at to make the
+// dispatch decision, so none of the session's bytes are lost.
+type ProtocolHandlers struct {
+	// V0 handles a protocol v0 session: a ref advertisement followed by a
+	// want/have negotiation, with no version preamble.
+	V0 func(rw io.ReadWriter, scanner *PacketScanner) error
+	// V1 handles a protocol v1 session. The wire shape is identical to v0;
+	// the only difference is that the client asked for v1 out-of-band.
+	V1 func(rw io.ReadWriter, scanner *PacketScanner) error
+	// V2 handles a protocol v2 session, beginning at the first
+	// "command=" packet.
+	V2 func(rw io.ReadWriter, req *ProtocolV2Request) error
+}
+
+// ProtocolMux inspects the first pkt-line of a session and an optional
+// out-of-band version hint to dispatch to the right one of ProtocolHandlers'
+// callbacks, letting a single entry point back both stateless-rpc HTTP
+// endpoints and stdio invocations of protocol v0, v1, and v2.
+type ProtocolMux struct {
+	// Version is the protocol version requested out-of-band, e.g. via a
+	// "Git-Protocol: version=2" HTTP request header or a GIT_PROTOCOL
+	// environment variable. A value of 2 always selects ProtocolHandlers.V2.
+	// A value of 0 (the zero value) means no preference was given, in which
+	// case ProtocolMux still recognizes v2 by sniffing for a leading
+	// "command=" packet, and otherwise falls back to ProtocolHandlers.V0.
+	Version int
+}
+
+// NewProtocolMuxFromGitProtocolHeader parses the value of a "Git-Protocol"
+// HTTP header (or a GIT_PROTOCOL environment variable, which uses the same
+// "key=value:key=value" syntax) and returns a ProtocolMux configured with the
+// version it requests.
+func NewProtocolMuxFromGitProtocolHeader(header string) *ProtocolMux {
+	m := &ProtocolMux{}
+	for _, kv := range strings.Split(header, ":") {
+		if v := strings.TrimPrefix(kv, "version="); v != kv {
+			if n, err := strconv.Atoi(v); err == nil {
+				m.Version = n
+			}
+		}
+	}
+	return m
+}
+
+// peekedReader is an io.Reader that replays a peeked packet before resuming
+// reads from the underlying reader, so that a packet consumed while
+// detecting the protocol version isn't lost to whichever scanner ends up
+// handling the rest of the session.
+type peekedReader struct {
+	peeked []byte
+	r      io.Reader
+}
+
+func (p *peekedReader) Read(buf []byte) (int, error) {
+	if len(p.peeked) > 0 {
+		n := copy(buf, p.peeked)
+		p.peeked = p.peeked[n:]
+		return n, nil
+	}
+	return p.r.Read(buf)
+}
+
+// Serve reads the first pkt-line of rw and dispatches to the matching
+// callback in handlers. It blocks until the chosen callback returns.
+func (m *ProtocolMux) Serve(rw io.ReadWriter, handlers ProtocolHandlers) error {
+	if m.Version == 2 {
+		if handlers.V2 == nil {
+			return fmt.Errorf("gitprotocolio: ProtocolMux: no V2 handler configured")
+		}
+		return handlers.V2(rw, NewProtocolV2Request(rw))
+	}
+
+	scanner := NewPacketScanner(rw)
+	if !scanner.Scan() {
+		return scanner.Err()
+	}
+	pkt := scanner.Packet()
+	peeked := &peekedReader{peeked: pkt.EncodeToPktLine(), r: rw}
+
+	if p, ok := pkt.(BytesPacket); ok && bytes.HasPrefix([]byte(p), []byte("command=")) {
+		if handlers.V2 == nil {
+			return fmt.Errorf("gitprotocolio: ProtocolMux: no V2 handler configured")
+		}
+		return handlers.V2(rw, NewProtocolV2Request(peeked))
+	}
+
+	primed := NewPacketScanner(peeked)
+	if !primed.Scan() {
+		if err := primed.Err(); err != nil {
+			return err
+		}
+	}
+	if m.Version == 1 {
+		if handlers.V1 == nil {
+			return fmt.Errorf("gitprotocolio: ProtocolMux: no V1 handler configured")
+		}
+		return handlers.V1(rw, primed)
+	}
+	if handlers.V0 == nil {
+		return fmt.Errorf("gitprotocolio: ProtocolMux: no V0 handler configured")
+	}
+	return handlers.V0(rw, primed)
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 The gitprotocolio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package gitprotocolio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestNewProtocolMuxFromGitProtocolHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"version 2", "version=2", 2},
+		{"multi key", "version=2:object-format=sha1", 2},
+		{"version 1", "version=1", 1},
+		{"no version key", "object-format=sha1", 0},
+		{"malformed version", "version=banana", 0},
+		{"empty header", "", 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NewProtocolMuxFromGitProtocolHeader(tc.header).Version; got != tc.want {
+				t.Errorf("NewProtocolMuxFromGitProtocolHeader(%q).Version = %d, want %d", tc.header, got, tc.want)
+			}
+		})
+	}
+}
+
+// readWriter pairs an independent input and output stream behind a single
+// io.ReadWriter, as a real connection would present one.
+type readWriter struct {
+	io.Reader
+	io.Writer
+}
+
+func TestProtocolMuxServeV2(t *testing.T) {
+	var input bytes.Buffer
+	input.Write(BytesPacket("command=fetch\n").EncodeToPktLine())
+	input.Write(FlushPacket{}.EncodeToPktLine())
+	rw := &readWriter{Reader: &input, Writer: &bytes.Buffer{}}
+
+	var gotCommand string
+	mux := &ProtocolMux{}
+	err := mux.Serve(rw, ProtocolHandlers{
+		V2: func(rw io.ReadWriter, req *ProtocolV2Request) error {
+			if !req.Scan() {
+				return req.Err()
+			}
+			gotCommand = req.Chunk().Command
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Serve() = %v, want nil", err)
+	}
+	if gotCommand != "fetch" {
+		t.Errorf("V2 handler saw command %q, want %q", gotCommand, "fetch")
+	}
+}
+
+func TestProtocolMuxServeForceV2(t *testing.T) {
+	var input bytes.Buffer
+	input.Write(BytesPacket("command=ls-refs\n").EncodeToPktLine())
+	input.Write(FlushPacket{}.EncodeToPktLine())
+	rw := &readWriter{Reader: &input, Writer: &bytes.Buffer{}}
+
+	called := false
+	mux := &ProtocolMux{Version: 2}
+	if err := mux.Serve(rw, ProtocolHandlers{
+		V2: func(rw io.ReadWriter, req *ProtocolV2Request) error {
+			called = true
+			return nil
+		},
+	}); err != nil {
+		t.Fatalf("Serve() = %v, want nil", err)
+	}
+	if !called {
+		t.Error("V2 handler was not called when Version was forced to 2")
+	}
+}
+
+func TestProtocolMuxServeV0Fallback(t *testing.T) {
+	var input bytes.Buffer
+	first := "0000000000000000000000000000000000000000 HEAD\x00report-status\n"
+	input.Write(BytesPacket(first).EncodeToPktLine())
+	input.Write(FlushPacket{}.EncodeToPktLine())
+	rw := &readWriter{Reader: &input, Writer: &bytes.Buffer{}}
+
+	var firstPacket BytesPacket
+	var v1Called bool
+	mux := &ProtocolMux{}
+	err := mux.Serve(rw, ProtocolHandlers{
+		V0: func(rw io.ReadWriter, scanner *PacketScanner) error {
+			firstPacket = scanner.Packet().(BytesPacket)
+			return nil
+		},
+		V1: func(rw io.ReadWriter, scanner *PacketScanner) error {
+			v1Called = true
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Serve() = %v, want nil", err)
+	}
+	if v1Called {
+		t.Error("V1 handler was called, want V0 as the default fallback")
+	}
+	if string(firstPacket) != first {
+		t.Errorf("V0 handler's primed packet = %q, want %q", firstPacket, first)
+	}
+}
+
+func TestProtocolMuxServeV1WhenRequested(t *testing.T) {
+	var input bytes.Buffer
+	input.Write(BytesPacket("0000000000000000000000000000000000000000 HEAD\x00report-status\n").EncodeToPktLine())
+	input.Write(FlushPacket{}.EncodeToPktLine())
+	rw := &readWriter{Reader: &input, Writer: &bytes.Buffer{}}
+
+	var v0Called bool
+	mux := &ProtocolMux{Version: 1}
+	err := mux.Serve(rw, ProtocolHandlers{
+		V0: func(rw io.ReadWriter, scanner *PacketScanner) error {
+			v0Called = true
+			return nil
+		},
+		V1: func(rw io.ReadWriter, scanner *PacketScanner) error {
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Serve() = %v, want nil", err)
+	}
+	if v0Called {
+		t.Error("V0 handler was called, want V1 since Version was set to 1")
+	}
+}
+
+func TestProtocolMuxServeEmptyFlushSession(t *testing.T) {
+	var input bytes.Buffer
+	input.Write(FlushPacket{}.EncodeToPktLine())
+	rw := &readWriter{Reader: &input, Writer: &bytes.Buffer{}}
+
+	var gotFlush bool
+	mux := &ProtocolMux{}
+	err := mux.Serve(rw, ProtocolHandlers{
+		V0: func(rw io.ReadWriter, scanner *PacketScanner) error {
+			_, gotFlush = scanner.Packet().(FlushPacket)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Serve() = %v, want nil", err)
+	}
+	if !gotFlush {
+		t.Error("V0 handler's primed packet was not the leading flush packet")
+	}
+}
+
+func TestProtocolMuxServeMissingHandler(t *testing.T) {
+	var input bytes.Buffer
+	input.Write(BytesPacket("command=fetch\n").EncodeToPktLine())
+	rw := &readWriter{Reader: &input, Writer: &bytes.Buffer{}}
+
+	mux := &ProtocolMux{}
+	if err := mux.Serve(rw, ProtocolHandlers{}); err == nil {
+		t.Error("Serve() = nil error, want an error when no V2 handler is configured")
+	}
+}
+
+func TestPeekedReaderReplaysPeekedBytesFirst(t *testing.T) {
+	p := &peekedReader{peeked: []byte("abc"), r: bytes.NewReader([]byte("def"))}
+	got, err := io.ReadAll(p)
+	if err != nil {
+		t.Fatalf("ReadAll() = %v", err)
+	}
+	if string(got) != "abcdef" {
+		t.Errorf("ReadAll() = %q, want %q", got, "abcdef")
+	}
+}